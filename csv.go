@@ -0,0 +1,129 @@
+package gojsonflatten
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// CSVOptions configures ToCSV and WriteCSV.
+type CSVOptions struct {
+	SeparatorStyle
+	Depth         int
+	PreserveArray bool
+	BaseHeaders   []string // Emitted before discovered headers, in order.
+	Delimiter     rune     // Field delimiter; defaults to ',' when zero.
+}
+
+// ToCSV flattens each element of jsonArray with opt's SeparatorStyle, Depth
+// and PreserveArray, then renders the rows as CSV records: a header row
+// followed by one row per element. The header row lists opt.BaseHeaders
+// first, then every other key discovered while flattening, in first-seen
+// row order (ties within a row are broken alphabetically, since a flattened
+// row is a map and carries no key order of its own). Keys missing from a
+// given row, and null values, are rendered as an empty string; other
+// non-string scalars are formatted with fmt.Sprint. A cell left un-flattened by opt.Depth or
+// opt.PreserveArray (a map or slice) is instead rendered as its JSON
+// encoding, so the CSV cell stays a valid, unambiguous representation of it.
+func ToCSV(jsonArray []map[string]interface{}, opt CSVOptions) ([][]string, error) {
+	flatRows := make([]map[string]interface{}, len(jsonArray))
+
+	seen := make(map[string]bool, len(opt.BaseHeaders))
+	headers := make([]string, 0, len(opt.BaseHeaders))
+	for _, h := range opt.BaseHeaders {
+		if !seen[h] {
+			seen[h] = true
+			headers = append(headers, h)
+		}
+	}
+
+	for i, nested := range jsonArray {
+		flat, err := flattenInternal(nested, "", opt.SeparatorStyle, opt.Depth, opt.PreserveArray)
+		if err != nil {
+			return nil, err
+		}
+		flatRows[i] = flat
+
+		var newKeys []string
+		for k := range flat {
+			if !seen[k] {
+				seen[k] = true
+				newKeys = append(newKeys, k)
+			}
+		}
+		sort.Strings(newKeys)
+		headers = append(headers, newKeys...)
+	}
+
+	records := make([][]string, 0, len(jsonArray)+1)
+	records = append(records, headers)
+
+	for _, flat := range flatRows {
+		record := make([]string, len(headers))
+		for i, h := range headers {
+			v, ok := flat[h]
+			if !ok || v == nil {
+				continue
+			}
+
+			cell, err := csvCell(v)
+			if err != nil {
+				return nil, err
+			}
+			record[i] = cell
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// csvCell renders a flattened value as a single CSV cell: a string as-is,
+// other scalars via fmt.Sprint, and a map or slice (left un-flattened by
+// Depth or PreserveArray) as its JSON encoding.
+func csvCell(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// WriteCSV flattens jsonArray (each element must be a map[string]interface{})
+// with opt and writes the result to w as CSV, using opt.Delimiter as the
+// field delimiter when set.
+func WriteCSV(w io.Writer, jsonArray []interface{}, opt CSVOptions) error {
+	rows := make([]map[string]interface{}, len(jsonArray))
+	for i, v := range jsonArray {
+		nested, ok := v.(map[string]interface{})
+		if !ok {
+			return ErrNotValidInput
+		}
+		rows[i] = nested
+	}
+
+	records, err := ToCSV(rows, opt)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if opt.Delimiter != 0 {
+		cw.Comma = opt.Delimiter
+	}
+
+	if err := cw.WriteAll(records); err != nil {
+		return err
+	}
+
+	return cw.Error()
+}
@@ -0,0 +1,90 @@
+package gojsonflatten
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToCSV(t *testing.T) {
+	jsonArray := []map[string]interface{}{
+		{
+			"name": "alice",
+			"addr": map[string]interface{}{
+				"city": "nyc",
+			},
+		},
+		{
+			"name": "bob",
+			"age":  30,
+		},
+	}
+
+	records, err := ToCSV(jsonArray, CSVOptions{
+		SeparatorStyle: DotStyle,
+		Depth:          -1,
+		BaseHeaders:    []string{"name"},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"name", "addr.city", "age"}, records[0])
+	assert.Equal(t, []string{"alice", "nyc", ""}, records[1])
+	assert.Equal(t, []string{"bob", "", "30"}, records[2])
+}
+
+func TestToCSVDepthCutoffRendersJSON(t *testing.T) {
+	jsonArray := []map[string]interface{}{
+		{
+			"a": map[string]interface{}{"b": map[string]interface{}{"c": "d"}},
+		},
+	}
+
+	records, err := ToCSV(jsonArray, CSVOptions{SeparatorStyle: DotStyle, Depth: 1})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"a.b"}, records[0])
+	assert.Equal(t, []string{`{"c":"d"}`}, records[1])
+}
+
+func TestToCSVPreserveArrayRendersJSON(t *testing.T) {
+	jsonArray := []map[string]interface{}{
+		{"a": []interface{}{"x", "y"}},
+	}
+
+	records, err := ToCSV(jsonArray, CSVOptions{SeparatorStyle: DotStyle, Depth: -1, PreserveArray: true})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"a"}, records[0])
+	assert.Equal(t, []string{`["x","y"]`}, records[1])
+}
+
+func TestWriteCSV(t *testing.T) {
+	jsonArray := []interface{}{
+		map[string]interface{}{"a": "1", "b": "2"},
+		map[string]interface{}{"a": "3", "b": "4"},
+	}
+
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, jsonArray, CSVOptions{SeparatorStyle: DotStyle, Depth: -1})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "a,b\n1,2\n3,4\n", buf.String())
+}
+
+func TestWriteCSVCustomDelimiter(t *testing.T) {
+	jsonArray := []interface{}{
+		map[string]interface{}{"a": "1", "b": "2"},
+	}
+
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, jsonArray, CSVOptions{SeparatorStyle: DotStyle, Depth: -1, Delimiter: ';'})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "a;b\n1;2\n", buf.String())
+}
+
+func TestWriteCSVInvalidElement(t *testing.T) {
+	err := WriteCSV(&bytes.Buffer{}, []interface{}{"not a map"}, CSVOptions{SeparatorStyle: DotStyle, Depth: -1})
+	assert.ErrorIs(t, err, ErrNotValidInput)
+}
@@ -0,0 +1,74 @@
+package gojsonflatten
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenEscapesSeparatorInKeys(t *testing.T) {
+	style := SeparatorStyle{Middle: ".", Escape: "\\"}
+
+	nested := map[string]interface{}{
+		"a.b": map[string]interface{}{
+			"c": "d",
+		},
+	}
+
+	flat, err := Flatten(nested, "", style, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{`a\.b.c`: "d"}, flat)
+}
+
+func TestFlattenUnflattenRoundTripsEscapedKeys(t *testing.T) {
+	style := SeparatorStyle{Middle: ".", Escape: "\\"}
+
+	nested := map[string]interface{}{
+		"a.b": map[string]interface{}{
+			"c.d": "e",
+		},
+	}
+
+	flat, err := Flatten(nested, "", style, -1)
+	assert.NoError(t, err)
+
+	got, err := Unflatten(flat, "", style)
+	assert.NoError(t, err)
+	assert.Equal(t, nested, got)
+}
+
+func TestFlattenStrictKeysRejectsAmbiguousKeys(t *testing.T) {
+	style := SeparatorStyle{Middle: ".", StrictKeys: true}
+
+	nested := map[string]interface{}{
+		"a.b": "c",
+	}
+
+	_, err := Flatten(nested, "", style, -1)
+	assert.Error(t, err)
+}
+
+func TestFlattenStrictKeysRejectsNestedAmbiguousKeys(t *testing.T) {
+	style := SeparatorStyle{Middle: ".", StrictKeys: true}
+
+	nested := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b.c": 1,
+		},
+	}
+
+	_, err := Flatten(nested, "", style, -1)
+	assert.Error(t, err)
+}
+
+func TestFlattenStrictKeysAllowsArrayIndices(t *testing.T) {
+	style := SeparatorStyle{Middle: ".", StrictKeys: true}
+
+	nested := map[string]interface{}{
+		"a": []interface{}{"x", "y"},
+	}
+
+	flat, err := Flatten(nested, "", style, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a.0": "x", "a.1": "y"}, flat)
+}
@@ -0,0 +1,183 @@
+package gojsonflatten
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FilterOptions holds JSONPath-like Include/Exclude patterns for
+// FlattenWithFilter. Patterns support `$`, `.key`, `[*]`, `[n]`, and `..`
+// (recursive descent), e.g. "$.foo.bar", "$.n1.alist[*].d", "$..jim".
+type FilterOptions struct {
+	Include []string // If non-empty, only leaves matching one of these patterns are emitted.
+	Exclude []string // Subtrees matching one of these patterns are pruned before recursion.
+}
+
+// FlattenWithFilter is like Flatten but prunes Exclude matches before
+// recursing into them and, when Include is non-empty, emits only leaves
+// matched by one of its patterns. With depth 0 it returns nested as-is, as
+// Flatten does, and no pattern is applied.
+func FlattenWithFilter(nested map[string]interface{}, prefix string, style SeparatorStyle, depth int, opts FilterOptions) (map[string]interface{}, error) {
+	filter, err := compileFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return flattenInternalFiltered(nested, prefix, style, depth, false, filter)
+}
+
+// compiledFilter is the tokenized form of a FilterOptions, ready to be
+// matched against the raw path segments flatten carries during recursion.
+type compiledFilter struct {
+	include [][]segMatcher
+	exclude [][]segMatcher
+}
+
+func compileFilter(opts FilterOptions) (*compiledFilter, error) {
+	if len(opts.Include) == 0 && len(opts.Exclude) == 0 {
+		return nil, nil
+	}
+
+	filter := &compiledFilter{}
+
+	for _, p := range opts.Include {
+		matchers, err := tokenizePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		filter.include = append(filter.include, matchers)
+	}
+
+	for _, p := range opts.Exclude {
+		matchers, err := tokenizePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		filter.exclude = append(filter.exclude, matchers)
+	}
+
+	return filter, nil
+}
+
+// excludes reports whether path is exactly matched by one of the compiled
+// Exclude patterns, i.e. path is the root of an excluded subtree.
+func (f *compiledFilter) excludes(path []string) bool {
+	for _, matchers := range f.exclude {
+		if matchSegments(matchers, path, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// includes reports whether path is selected by the compiled Include
+// patterns. A pattern matching an ancestor of path selects the whole
+// subtree, so matching allows a path suffix beyond the pattern's end.
+// With no Include patterns at all, every path is included.
+func (f *compiledFilter) includes(path []string) bool {
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, matchers := range f.include {
+		if matchSegments(matchers, path, true) {
+			return true
+		}
+	}
+	return false
+}
+
+// segMatcherKind is the kind of a single tokenized JSONPath segment.
+type segMatcherKind int
+
+const (
+	matchLiteral segMatcherKind = iota
+	matchWildcard
+	matchDescend
+)
+
+// segMatcher matches one segment (or, for matchDescend, zero or more
+// segments) of a path.
+type segMatcher struct {
+	kind  segMatcherKind
+	value string
+}
+
+// tokenizePattern parses a JSONPath-like expression into a slice of segment
+// matchers, e.g. "$.n1.alist[*].d" -> [n1, alist, *, d].
+func tokenizePattern(pattern string) ([]segMatcher, error) {
+	s := strings.TrimPrefix(pattern, "$")
+
+	var matchers []segMatcher
+	for i := 0; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], ".."):
+			matchers = append(matchers, segMatcher{kind: matchDescend})
+			i += 2
+		case s[i] == '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("gojsonflatten: unterminated [ in pattern %q", pattern)
+			}
+			inner := s[i+1 : i+end]
+			if inner == "*" {
+				matchers = append(matchers, segMatcher{kind: matchWildcard})
+			} else {
+				matchers = append(matchers, segMatcher{kind: matchLiteral, value: inner})
+			}
+			i += end + 1
+		default:
+			// A literal segment: either ".foo" (consume the leading dot) or,
+			// directly after ".." recursive descent, a bare "foo" with no
+			// separator of its own.
+			j := i
+			if s[j] == '.' {
+				j++
+			}
+			start := j
+			for j < len(s) && s[j] != '.' && s[j] != '[' {
+				j++
+			}
+			if j == start {
+				return nil, fmt.Errorf("gojsonflatten: invalid pattern %q", pattern)
+			}
+			matchers = append(matchers, segMatcher{kind: matchLiteral, value: s[start:j]})
+			i = j
+		}
+	}
+
+	return matchers, nil
+}
+
+// matchSegments matches matchers against path segment-by-segment. When
+// allowSuffix is true, fully consuming matchers is a match even if path has
+// segments left over (the pattern selected an ancestor of path).
+func matchSegments(matchers []segMatcher, path []string, allowSuffix bool) bool {
+	if len(matchers) == 0 {
+		return allowSuffix || len(path) == 0
+	}
+
+	head := matchers[0]
+
+	if head.kind == matchDescend {
+		rest := matchers[1:]
+		for k := 0; k <= len(path); k++ {
+			if matchSegments(rest, path[k:], allowSuffix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(path) == 0 {
+		return false
+	}
+
+	switch head.kind {
+	case matchWildcard:
+		return matchSegments(matchers[1:], path[1:], allowSuffix)
+	case matchLiteral:
+		return head.value == path[0] && matchSegments(matchers[1:], path[1:], allowSuffix)
+	default:
+		return false
+	}
+}
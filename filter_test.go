@@ -0,0 +1,82 @@
+package gojsonflatten
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenWithFilter(t *testing.T) {
+	input := `{
+		"foo": {
+			"jim":"bean"
+		},
+		"fee": "bar",
+		"n1": {
+			"alist": [
+				"a",
+				"b",
+				"c",
+				{
+					"d": "other",
+					"e": "another"
+				}
+			]
+		}
+	}`
+
+	cases := []struct {
+		test string
+		opts FilterOptions
+		want map[string]interface{}
+	}{
+		{
+			"include a single leaf",
+			FilterOptions{Include: []string{"$.foo.jim"}},
+			map[string]interface{}{"foo.jim": "bean"},
+		},
+		{
+			"include with array wildcard",
+			FilterOptions{Include: []string{"$.n1.alist[*].d"}},
+			map[string]interface{}{"n1.alist.3.d": "other"},
+		},
+		{
+			"include recursive descent",
+			FilterOptions{Include: []string{"$..jim"}},
+			map[string]interface{}{"foo.jim": "bean"},
+		},
+		{
+			"include an ancestor selects the whole subtree",
+			FilterOptions{Include: []string{"$.foo"}},
+			map[string]interface{}{"foo.jim": "bean"},
+		},
+		{
+			"exclude a subtree",
+			FilterOptions{Exclude: []string{"$.n1"}},
+			map[string]interface{}{"foo.jim": "bean", "fee": "bar"},
+		},
+		{
+			"exclude wins over a broader include",
+			FilterOptions{Include: []string{"$..d", "$..e"}, Exclude: []string{"$.n1.alist[*].e"}},
+			map[string]interface{}{"n1.alist.3.d": "other"},
+		},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("test: %v (%s)", i, test.test), func(t *testing.T) {
+			var nested map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(input), &nested))
+
+			got, err := FlattenWithFilter(nested, "", DotStyle, -1, test.opts)
+			assert.NoError(t, err)
+			deepEquals(t, i, got, test.want)
+		})
+	}
+}
+
+func TestFlattenWithFilterInvalidPattern(t *testing.T) {
+	_, err := FlattenWithFilter(map[string]interface{}{"a": "b"}, "", DotStyle, -1, FilterOptions{Include: []string{"$.a["}})
+	assert.Error(t, err)
+}
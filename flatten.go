@@ -4,8 +4,10 @@ package gojsonflatten
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 // SeparatorStyle defines the style of keys when flattening nested structures.
@@ -14,6 +16,17 @@ type SeparatorStyle struct {
 	Before string // Prepend to key
 	Middle string // Add between keys
 	After  string // Append to key
+
+	// Escape, when non-empty, is prepended to any occurrence of Before,
+	// Middle, or After found inside a key before it is concatenated in
+	// enkey, so such keys don't collide with the separator. Unflatten
+	// reverses this. Array indices are never escaped.
+	Escape string
+
+	// StrictKeys makes flattening fail with an error when a key contains
+	// Before, Middle, or After and Escape is empty, instead of silently
+	// producing an ambiguous flat key.
+	StrictKeys bool
 }
 
 // Default SeparatorStyles
@@ -52,6 +65,12 @@ func FlattenStringNoArray(nestedString, prefix string, style SeparatorStyle, dep
 
 // flattenInternal generates a flat map from a nested map with a specified depth, optionally preserving arrays as strings.
 func flattenInternal(nested map[string]interface{}, prefix string, style SeparatorStyle, depth int, preserveArray bool) (map[string]interface{}, error) {
+	return flattenInternalFiltered(nested, prefix, style, depth, preserveArray, nil)
+}
+
+// flattenInternalFiltered is flattenInternal plus an optional compiled
+// Include/Exclude filter; a nil filter behaves exactly like flattenInternal.
+func flattenInternalFiltered(nested map[string]interface{}, prefix string, style SeparatorStyle, depth int, preserveArray bool, filter *compiledFilter) (map[string]interface{}, error) {
 	if depth == 0 {
 		return nested, nil
 	} else if depth > 0 {
@@ -59,7 +78,7 @@ func flattenInternal(nested map[string]interface{}, prefix string, style Separat
 	}
 
 	flatmap := make(map[string]interface{})
-	err := flatten(true, flatmap, nested, prefix, style, depth, preserveArray)
+	err := flatten(true, flatmap, nested, prefix, style, depth, preserveArray, nil, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -92,24 +111,38 @@ func flattenStringInternal(nestedString, prefix string, style SeparatorStyle, de
 	return string(flatBytes), nil
 }
 
-// flatten recursively processes nested structures and flattens them.
-func flatten(top bool, flatMap map[string]interface{}, nested interface{}, prefix string, style SeparatorStyle, depth int, keepArrays bool) error {
+// flatten recursively processes nested structures and flattens them. path
+// tracks the raw (unstyled) key/index segments seen so far, for matching
+// against a filter; filter is nil when no Include/Exclude patterns apply.
+func flatten(top bool, flatMap map[string]interface{}, nested interface{}, prefix string, style SeparatorStyle, depth int, keepArrays bool, path []string, filter *compiledFilter) error {
 	if depth == 0 {
 		// If the desired depth is reached, add the prefix and nested value to the flat map.
+		if filter != nil && (filter.excludes(path) || !filter.includes(path)) {
+			return nil
+		}
 		flatMap[prefix] = nested
 		return nil
 	}
 
 	// Assign function is used to process and assign values to the flat map.
-	assign := func(newKey string, v interface{}) error {
+	assign := func(newKey, segment string, v interface{}) error {
+		newPath := append(append(make([]string, 0, len(path)+1), path...), segment)
+		if filter != nil && filter.excludes(newPath) {
+			// Prune the subtree entirely: don't recurse and don't emit it.
+			return nil
+		}
+
 		switch v.(type) {
 		case map[string]interface{}, []interface{}:
 			// If the value is a nested map or slice, continue flattening recursively.
-			if err := flatten(false, flatMap, v, newKey, style, depth-1, keepArrays); err != nil {
+			if err := flatten(false, flatMap, v, newKey, style, depth-1, keepArrays, newPath, filter); err != nil {
 				return err
 			}
 		default:
 			// For scalar values, directly add them to the flat map.
+			if filter != nil && !filter.includes(newPath) {
+				return nil
+			}
 			flatMap[newKey] = v
 		}
 
@@ -119,19 +152,32 @@ func flatten(top bool, flatMap map[string]interface{}, nested interface{}, prefi
 	switch nested := nested.(type) {
 	case map[string]interface{}:
 		for k, v := range nested {
-			newKey := enkey(top, prefix, k, style)
+			newKey, err := enkey(top, prefix, k, style, false)
+			if err != nil {
+				return err
+			}
 			// Process and assign the key-value pair.
-			assign(newKey, v)
+			if err := assign(newKey, k, v); err != nil {
+				return err
+			}
 		}
 	case []interface{}:
 		if !keepArrays {
 			for i, v := range nested {
-				newKey := enkey(top, prefix, strconv.Itoa(i), style)
+				index := strconv.Itoa(i)
+				newKey, err := enkey(top, prefix, index, style, true)
+				if err != nil {
+					return err
+				}
 				// Process and assign the index-value pair.
-				assign(newKey, v)
+				if err := assign(newKey, index, v); err != nil {
+					return err
+				}
 			}
 		} else {
-			flatMap[prefix] = nested
+			if filter == nil || (!filter.excludes(path) && filter.includes(path)) {
+				flatMap[prefix] = nested
+			}
 		}
 	default:
 		return ErrNotValidInput
@@ -140,8 +186,18 @@ func flatten(top bool, flatMap map[string]interface{}, nested interface{}, prefi
 	return nil
 }
 
-// enkey combines the prefix, subKey, and SeparatorStyle to form a new key.
-func enkey(top bool, prefix, subKey string, style SeparatorStyle) string {
+// enkey combines the prefix, subKey, and SeparatorStyle to form a new key,
+// escaping or rejecting subKey per style.Escape/style.StrictKeys. isIndex
+// marks subKey as an array index, which is never escaped or checked.
+func enkey(top bool, prefix, subKey string, style SeparatorStyle, isIndex bool) (string, error) {
+	if !isIndex {
+		if style.Escape != "" {
+			subKey = escapeKey(subKey, style)
+		} else if style.StrictKeys && containsSeparator(subKey, style) {
+			return "", fmt.Errorf("gojsonflatten: key %q contains a separator character; set Escape or disable StrictKeys", subKey)
+		}
+	}
+
 	key := prefix
 
 	if top {
@@ -152,5 +208,27 @@ func enkey(top bool, prefix, subKey string, style SeparatorStyle) string {
 		key += style.Before + style.Middle + subKey + style.After
 	}
 
-	return key
+	return key, nil
+}
+
+// containsSeparator reports whether subKey contains any of style's
+// non-empty Before, Middle, or After strings.
+func containsSeparator(subKey string, style SeparatorStyle) bool {
+	for _, sep := range [...]string{style.Before, style.Middle, style.After} {
+		if sep != "" && strings.Contains(subKey, sep) {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeKey prepends style.Escape to every occurrence of style's non-empty
+// Before, Middle, and After strings found inside subKey.
+func escapeKey(subKey string, style SeparatorStyle) string {
+	for _, sep := range [...]string{style.Before, style.Middle, style.After} {
+		if sep != "" {
+			subKey = strings.ReplaceAll(subKey, sep, style.Escape+sep)
+		}
+	}
+	return subKey
 }
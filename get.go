@@ -0,0 +1,88 @@
+package gojsonflatten
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Result is a single match returned by Get.
+type Result struct {
+	Key     string
+	Value   interface{}
+	Indexes []int // The array index matched by each '#' in the pattern, in pattern order.
+}
+
+// Get queries a flat map produced by Flatten for every entry whose key
+// matches pattern under style. The pattern is split into segments the same
+// way style splits a flattened key (so RailsStyle's asymmetric first-vs-rest
+// encoding is handled automatically); within those segments, '*' matches any
+// single segment and '#' matches any array-index segment, recording its
+// numeric value in Result.Indexes. Results are sorted by Indexes (falling
+// back to Key for ties or patterns with no '#') so matches within an array
+// come back in index order rather than lexicographic key order. This gives
+// a lightweight query layer over a flat map without re-nesting it via
+// Unflatten.
+func Get(flat map[string]interface{}, pattern string, style SeparatorStyle) ([]Result, error) {
+	patternSegments, err := dekey(pattern, "", style)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for key, value := range flat {
+		keySegments, err := dekey(key, "", style)
+		if err != nil {
+			return nil, err
+		}
+
+		indexes, ok := matchGetPattern(patternSegments, keySegments)
+		if !ok {
+			continue
+		}
+
+		results = append(results, Result{Key: key, Value: value, Indexes: indexes})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i].Indexes, results[j].Indexes
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return results[i].Key < results[j].Key
+	})
+
+	return results, nil
+}
+
+// matchGetPattern compares pattern against key segment-by-segment, honoring
+// '*' (any segment) and '#' (any array index, recorded in order).
+func matchGetPattern(pattern, key []string) ([]int, bool) {
+	if len(pattern) != len(key) {
+		return nil, false
+	}
+
+	var indexes []int
+	for i, p := range pattern {
+		switch p {
+		case "*":
+			continue
+		case "#":
+			n, err := strconv.Atoi(key[i])
+			if err != nil {
+				return nil, false
+			}
+			indexes = append(indexes, n)
+		default:
+			if p != key[i] {
+				return nil, false
+			}
+		}
+	}
+
+	return indexes, true
+}
@@ -0,0 +1,68 @@
+package gojsonflatten
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	input := `{
+		"foo": {
+			"jim":"bean"
+		},
+		"n1": {
+			"alist": [
+				"a",
+				"b",
+				"c",
+				{
+					"d": "other",
+					"e": "another"
+				}
+			]
+		}
+	}`
+
+	var nested map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(input), &nested))
+
+	flat, err := Flatten(nested, "", DotStyle, -1)
+	assert.NoError(t, err)
+
+	results, err := Get(flat, "n1.alist.#.d", DotStyle)
+	assert.NoError(t, err)
+	assert.Equal(t, []Result{{Key: "n1.alist.3.d", Value: "other", Indexes: []int{3}}}, results)
+
+	results, err = Get(flat, "n1.alist.#", DotStyle)
+	assert.NoError(t, err)
+	assert.Equal(t, []Result{
+		{Key: "n1.alist.0", Value: "a", Indexes: []int{0}},
+		{Key: "n1.alist.1", Value: "b", Indexes: []int{1}},
+		{Key: "n1.alist.2", Value: "c", Indexes: []int{2}},
+	}, results)
+
+	results, err = Get(flat, "*.jim", DotStyle)
+	assert.NoError(t, err)
+	assert.Equal(t, []Result{{Key: "foo.jim", Value: "bean", Indexes: nil}}, results)
+}
+
+func TestGetRailsStyle(t *testing.T) {
+	flat := map[string]interface{}{
+		"n1[alist][0]":    "a",
+		"n1[alist][3][d]": "other",
+	}
+
+	results, err := Get(flat, "n1[alist][#][d]", RailsStyle)
+	assert.NoError(t, err)
+	assert.Equal(t, []Result{{Key: "n1[alist][3][d]", Value: "other", Indexes: []int{3}}}, results)
+}
+
+func TestGetNoMatches(t *testing.T) {
+	flat := map[string]interface{}{"a.b": "c"}
+
+	results, err := Get(flat, "x.y", DotStyle)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
@@ -0,0 +1,271 @@
+package gojsonflatten
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// FlattenReader streams a single large JSON object from r, flattening it
+// with the given prefix, style and depth, and writes the result as a JSON
+// object to w. Unlike FlattenString it never materializes the full nested
+// document in memory: it drives an encoding/json.Decoder token-by-token and
+// emits each flattened "key":value pair to w as soon as it is known.
+//
+// When preserveArray is true, arrays are re-encoded verbatim (like
+// FlattenNoArray) instead of being flattened element-by-element. When depth
+// is reached, the remaining subtree at that point is buffered and emitted
+// as-is, the same way flattenInternal does for in-memory flattening.
+func FlattenReader(r io.Reader, w io.Writer, prefix string, style SeparatorStyle, depth int, preserveArray bool) error {
+	if depth == 0 {
+		br := bufio.NewReader(r)
+		if err := requireJSONObject(br); err != nil {
+			return err
+		}
+		_, err := io.Copy(w, br)
+		return err
+	} else if depth > 0 {
+		depth++
+	}
+
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return ErrNotValidJsonInput
+	}
+
+	sw := &streamWriter{w: w}
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return err
+	}
+
+	if err := flattenReaderObject(dec, sw, prefix, style, depth, preserveArray, true); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "}")
+	return err
+}
+
+// requireJSONObject peeks past any leading JSON whitespace in br, without
+// consuming it, and confirms the input opens a JSON object - the same
+// contract isJsonMap enforces for FlattenString.
+func requireJSONObject(br *bufio.Reader) error {
+	peeked, _ := br.Peek(512)
+	if !isJsonMap.Match(peeked) {
+		return ErrNotValidJsonInput
+	}
+	return nil
+}
+
+// streamWriter emits "key":value pairs to an underlying writer, inserting
+// commas between successive pairs.
+type streamWriter struct {
+	w       io.Writer
+	emitted bool
+}
+
+func (sw *streamWriter) emit(key string, value interface{}) error {
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return sw.emitRaw(key, valueBytes)
+}
+
+func (sw *streamWriter) emitRaw(key string, raw json.RawMessage) error {
+	if sw.emitted {
+		if _, err := io.WriteString(sw.w, ","); err != nil {
+			return err
+		}
+	}
+	sw.emitted = true
+
+	keyBytes, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	if _, err := sw.w.Write(keyBytes); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(sw.w, ":"); err != nil {
+		return err
+	}
+	_, err = sw.w.Write(raw)
+	return err
+}
+
+// flattenReaderObject consumes the key/value pairs of an object whose
+// opening '{' has already been read from dec, emitting or recursing into
+// each field under prefix.
+func flattenReaderObject(dec *json.Decoder, sw *streamWriter, prefix string, style SeparatorStyle, depth int, preserveArray, top bool) error {
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := keyTok.(string)
+		newKey, err := enkey(top, prefix, key, style, false)
+		if err != nil {
+			return err
+		}
+
+		if err := flattenReaderValue(dec, sw, newKey, style, depth-1, preserveArray); err != nil {
+			return err
+		}
+	}
+
+	_, err := dec.Token() // consume closing '}'
+	return err
+}
+
+// flattenReaderArray consumes the elements of an array whose opening '[' has
+// already been read from dec, emitting or recursing into each index under
+// prefix.
+func flattenReaderArray(dec *json.Decoder, sw *streamWriter, prefix string, style SeparatorStyle, depth int, preserveArray, top bool) error {
+	for i := 0; dec.More(); i++ {
+		newKey, err := enkey(top, prefix, strconv.Itoa(i), style, true)
+		if err != nil {
+			return err
+		}
+
+		if err := flattenReaderValue(dec, sw, newKey, style, depth-1, preserveArray); err != nil {
+			return err
+		}
+	}
+
+	_, err := dec.Token() // consume closing ']'
+	return err
+}
+
+// flattenReaderValue reads the next JSON value from dec and either assigns
+// it directly to newKey (scalars, depth cutoff, preserved arrays) or
+// recurses into it.
+func flattenReaderValue(dec *json.Decoder, sw *streamWriter, newKey string, style SeparatorStyle, depth int, preserveArray bool) error {
+	if depth == 0 {
+		raw, err := captureValue(dec)
+		if err != nil {
+			return err
+		}
+		return sw.emitRaw(newKey, raw)
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, isDelim := tok.(json.Delim)
+	if !isDelim {
+		return sw.emit(newKey, tok)
+	}
+
+	switch delim {
+	case '{':
+		return flattenReaderObject(dec, sw, newKey, style, depth, preserveArray, false)
+	case '[':
+		if preserveArray {
+			var buf bytes.Buffer
+			if err := copyValue(dec, &buf, tok); err != nil {
+				return err
+			}
+			return sw.emitRaw(newKey, buf.Bytes())
+		}
+		return flattenReaderArray(dec, sw, newKey, style, depth, preserveArray, false)
+	default:
+		return fmt.Errorf("gojsonflatten: unexpected delimiter %v", delim)
+	}
+}
+
+// captureValue reads exactly one JSON value (scalar, object, or array) from
+// dec and re-encodes it verbatim into a json.RawMessage.
+func captureValue(dec *json.Decoder) (json.RawMessage, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := copyValue(dec, &buf, tok); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// copyValue writes tok (the already-read first token of a JSON value) and,
+// if it opens an object or array, the rest of that structure's tokens from
+// dec, to buf - reconstructing the value's JSON encoding verbatim.
+func copyValue(dec *json.Decoder, buf *bytes.Buffer, tok json.Token) error {
+	delim, isDelim := tok.(json.Delim)
+	if !isDelim {
+		b, err := json.Marshal(tok)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	}
+
+	switch delim {
+	case '{':
+		buf.WriteByte('{')
+		for first := true; dec.More(); first = false {
+			if !first {
+				buf.WriteByte(',')
+			}
+
+			keyTok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			keyBytes, err := json.Marshal(keyTok)
+			if err != nil {
+				return err
+			}
+			buf.Write(keyBytes)
+			buf.WriteByte(':')
+
+			valTok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if err := copyValue(dec, buf, valTok); err != nil {
+				return err
+			}
+		}
+		if _, err := dec.Token(); err != nil { // consume closing '}'
+			return err
+		}
+		buf.WriteByte('}')
+	case '[':
+		buf.WriteByte('[')
+		for first := true; dec.More(); first = false {
+			if !first {
+				buf.WriteByte(',')
+			}
+
+			valTok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if err := copyValue(dec, buf, valTok); err != nil {
+				return err
+			}
+		}
+		if _, err := dec.Token(); err != nil { // consume closing ']'
+			return err
+		}
+		buf.WriteByte(']')
+	default:
+		return fmt.Errorf("gojsonflatten: unexpected delimiter %v", delim)
+	}
+
+	return nil
+}
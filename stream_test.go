@@ -0,0 +1,140 @@
+package gojsonflatten
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenReader(t *testing.T) {
+	input := `{
+		"foo": {
+			"jim":"bean"
+		},
+		"fee": "bar",
+		"n1": {
+			"alist": [
+				"a",
+				"b",
+				"c",
+				{
+					"d": "other",
+					"e": "another"
+				}
+			]
+		},
+		"number": 1.4567,
+		"bool":   true
+	}`
+
+	want := map[string]interface{}{
+		"foo.jim":      "bean",
+		"fee":          "bar",
+		"n1.alist.0":   "a",
+		"n1.alist.1":   "b",
+		"n1.alist.2":   "c",
+		"n1.alist.3.d": "other",
+		"n1.alist.3.e": "another",
+		"number":       1.4567,
+		"bool":         true,
+	}
+
+	var out bytes.Buffer
+	err := FlattenReader(strings.NewReader(input), &out, "", DotStyle, -1, false)
+	assert.NoError(t, err)
+
+	var got map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	deepEquals(t, 0, got, want)
+}
+
+func TestFlattenReaderPreserveArray(t *testing.T) {
+	input := `{ "a": { "b": ["x", "y"] } }`
+
+	var out bytes.Buffer
+	err := FlattenReader(strings.NewReader(input), &out, "", DotStyle, -1, true)
+	assert.NoError(t, err)
+
+	var got map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	deepEquals(t, 0, got, map[string]interface{}{
+		"a.b": []interface{}{"x", "y"},
+	})
+}
+
+func TestFlattenReaderDepth(t *testing.T) {
+	input := `{ "a": { "b": { "c": "d" } }, "g": "h" }`
+
+	var out bytes.Buffer
+	err := FlattenReader(strings.NewReader(input), &out, "", DotStyle, 1, false)
+	assert.NoError(t, err)
+
+	var got map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	deepEquals(t, 0, got, map[string]interface{}{
+		"a.b": map[string]interface{}{
+			"c": "d",
+		},
+		"g": "h",
+	})
+}
+
+func TestFlattenReaderMatchesFlattenString(t *testing.T) {
+	inputs := []string{
+		`{ "a": { "b" : { "c" : { "d" : "e" } } }, "number": 1.4567, "bool": true }`,
+		`{ "n1": { "alist": ["a", "b", { "d": "other" }] } }`,
+	}
+
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("test: %v", i), func(t *testing.T) {
+			wantString, err := FlattenString(input, "", DotStyle, -1)
+			assert.NoError(t, err)
+			var want map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(wantString), &want))
+
+			var out bytes.Buffer
+			assert.NoError(t, FlattenReader(strings.NewReader(input), &out, "", DotStyle, -1, false))
+			var got map[string]interface{}
+			assert.NoError(t, json.Unmarshal(out.Bytes(), &got))
+
+			deepEquals(t, i, got, want)
+		})
+	}
+}
+
+func TestFlattenReaderInvalidJSON(t *testing.T) {
+	var out bytes.Buffer
+	err := FlattenReader(strings.NewReader(`[ "a" ]`), &out, "", DotStyle, -1, false)
+	assert.ErrorIs(t, err, ErrNotValidJsonInput)
+}
+
+func TestFlattenReaderDepthZero(t *testing.T) {
+	input := `{ "a": { "b": "c" } }`
+
+	var out bytes.Buffer
+	err := FlattenReader(strings.NewReader(input), &out, "", DotStyle, 0, false)
+	assert.NoError(t, err)
+
+	var got map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	deepEquals(t, 0, got, map[string]interface{}{
+		"a": map[string]interface{}{"b": "c"},
+	})
+}
+
+func TestFlattenReaderDepthZeroInvalidJSON(t *testing.T) {
+	cases := []string{`[ "a" ]`, `not json at all`, ``}
+
+	for i, input := range cases {
+		t.Run(fmt.Sprintf("test: %v", i), func(t *testing.T) {
+			var out bytes.Buffer
+			err := FlattenReader(strings.NewReader(input), &out, "", DotStyle, 0, false)
+			assert.ErrorIs(t, err, ErrNotValidJsonInput)
+			assert.Empty(t, out.String())
+		})
+	}
+}
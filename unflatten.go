@@ -0,0 +1,235 @@
+package gojsonflatten
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Unflatten reconstructs a nested map from a flat map produced by Flatten,
+// reversing the key composition described by style. Whenever every key at a
+// given level below the root is a contiguous integer sequence starting at 0,
+// that level is re-materialized as a slice instead of a map; the root itself
+// is always returned as a map.
+func Unflatten(flat map[string]interface{}, prefix string, style SeparatorStyle) (map[string]interface{}, error) {
+	nested := make(map[string]interface{})
+
+	for key, value := range flat {
+		segments, err := dekey(key, prefix, style)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := setPath(nested, segments, value); err != nil {
+			return nil, err
+		}
+	}
+
+	// The root is always a map per Unflatten's signature, even if its keys
+	// happen to form a contiguous 0..n-1 sequence - only arrayify children.
+	for k, v := range nested {
+		nested[k] = arrayify(v)
+	}
+
+	return nested, nil
+}
+
+// UnflattenString reconstructs a nested JSON string from a flat JSON string
+// produced by FlattenString.
+func UnflattenString(flatString, prefix string, style SeparatorStyle) (string, error) {
+	if !isJsonMap.MatchString(flatString) {
+		return "", ErrNotValidJsonInput
+	}
+
+	var flat map[string]interface{}
+	err := json.Unmarshal([]byte(flatString), &flat)
+	if err != nil {
+		return "", err
+	}
+
+	nested, err := Unflatten(flat, prefix, style)
+	if err != nil {
+		return "", err
+	}
+
+	nestedBytes, err := json.Marshal(&nested)
+	if err != nil {
+		return "", err
+	}
+
+	return string(nestedBytes), nil
+}
+
+// setPath walks (creating as needed) the nested maps described by segments
+// and assigns value at the final segment.
+func setPath(root map[string]interface{}, segments []string, value interface{}) error {
+	cur := root
+
+	for i, segment := range segments {
+		if i == len(segments)-1 {
+			cur[segment] = value
+			return nil
+		}
+
+		next, ok := cur[segment]
+		if !ok {
+			child := make(map[string]interface{})
+			cur[segment] = child
+			cur = child
+			continue
+		}
+
+		child, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("gojsonflatten: conflicting value already set at %q", segment)
+		}
+		cur = child
+	}
+
+	return nil
+}
+
+// arrayify recursively converts any map whose keys form a contiguous 0..n-1
+// integer sequence into a slice, leaving everything else untouched.
+func arrayify(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	for k, child := range m {
+		m[k] = arrayify(child)
+	}
+
+	if !isIndexedMap(m) {
+		return m
+	}
+
+	arr := make([]interface{}, len(m))
+	for k, child := range m {
+		i, _ := strconv.Atoi(k)
+		arr[i] = child
+	}
+
+	return arr
+}
+
+// isIndexedMap reports whether m's keys are exactly the canonical decimal
+// strings "0".."len(m)-1" - not merely strings that parse to that range,
+// which would let distinct keys like "0" and "00" collide on the same index.
+func isIndexedMap(m map[string]interface{}) bool {
+	if len(m) == 0 {
+		return false
+	}
+
+	for k := range m {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(m) || strconv.Itoa(i) != k {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dekey splits a flattened key into its path segments according to style,
+// the inverse of enkey. When style.Escape is set, occurrences of Before,
+// Middle, or After that enkey escaped inside a segment are recognized and
+// unescaped rather than treated as a split point.
+func dekey(key, prefix string, style SeparatorStyle) ([]string, error) {
+	if !strings.HasPrefix(key, prefix) {
+		return nil, fmt.Errorf("gojsonflatten: key %q does not have prefix %q", key, prefix)
+	}
+	rest := key[len(prefix):]
+
+	sep := style.Before + style.Middle
+
+	var segments []string
+	switch {
+	case style.After == "":
+		if sep == "" {
+			segments = []string{rest}
+		} else {
+			segments = splitEscaped(rest, sep, style.Escape)
+		}
+	case sep == "":
+		segments = []string{rest}
+	default:
+		idx := indexEscaped(rest, sep, style.Escape)
+		if idx < 0 {
+			segments = []string{rest}
+			break
+		}
+
+		segments = []string{rest[:idx]}
+		remainder := rest[idx:]
+		for len(remainder) > 0 {
+			remainder = strings.TrimPrefix(remainder, sep)
+			end := indexEscaped(remainder, style.After, style.Escape)
+			if end < 0 {
+				break
+			}
+			segments = append(segments, remainder[:end])
+			remainder = remainder[end+len(style.After):]
+		}
+	}
+
+	if style.Escape != "" {
+		for i, seg := range segments {
+			segments[i] = unescapeKey(seg, style)
+		}
+	}
+
+	return segments, nil
+}
+
+// indexEscaped returns the index of the first occurrence of sub in s that
+// isn't itself preceded by escape (and therefore wasn't produced by
+// escapeKey), or -1 if there is none.
+func indexEscaped(s, sub, escape string) int {
+	if sub == "" {
+		return -1
+	}
+
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if s[i:i+len(sub)] != sub {
+			continue
+		}
+		if escape != "" && i >= len(escape) && s[i-len(escape):i] == escape {
+			continue
+		}
+		return i
+	}
+
+	return -1
+}
+
+// splitEscaped splits s on every unescaped occurrence of sep.
+func splitEscaped(s, sep, escape string) []string {
+	var parts []string
+	for {
+		idx := indexEscaped(s, sep, escape)
+		if idx < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:idx])
+		s = s[idx+len(sep):]
+	}
+}
+
+// unescapeKey reverses escapeKey, removing style.Escape from before any
+// occurrence of style's Before, Middle, or After.
+func unescapeKey(seg string, style SeparatorStyle) string {
+	if style.Escape == "" {
+		return seg
+	}
+
+	for _, sep := range [...]string{style.Before, style.Middle, style.After} {
+		if sep != "" {
+			seg = strings.ReplaceAll(seg, style.Escape+sep, sep)
+		}
+	}
+
+	return seg
+}
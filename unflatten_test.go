@@ -0,0 +1,162 @@
+package gojsonflatten
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnflatten(t *testing.T) {
+	cases := []struct {
+		test   string
+		flat   map[string]interface{}
+		prefix string
+		style  SeparatorStyle
+	}{
+		// Test case 1
+		{
+			"dot style",
+			map[string]interface{}{
+				"foo.jim":      "bean",
+				"fee":          "bar",
+				"n1.alist.0":   "a",
+				"n1.alist.1":   "b",
+				"n1.alist.2":   "c",
+				"n1.alist.3.d": "other",
+				"n1.alist.3.e": "another",
+				"number":       1.4567,
+				"bool":         true,
+			},
+			"",
+			DotStyle,
+		},
+		// Test case 2
+		{
+			"rails style",
+			map[string]interface{}{
+				"foo[jim]":        "bean",
+				"fee":             "bar",
+				"n1[alist][0]":    "a",
+				"n1[alist][1]":    "b",
+				"n1[alist][2]":    "c",
+				"n1[alist][3][d]": "other",
+				"n1[alist][3][e]": "another",
+			},
+			"",
+			RailsStyle,
+		},
+		// Test case 3
+		{
+			"path style",
+			map[string]interface{}{
+				"foo/jim":      "bean",
+				"fee":          "bar",
+				"n1/alist/0":   "a",
+				"n1/alist/1":   "b",
+				"n1/alist/2":   "c",
+				"n1/alist/3/d": "other",
+				"n1/alist/3/e": "another",
+			},
+			"",
+			PathStyle,
+		},
+		// Test case 4
+		{
+			"underscore style with prefix",
+			map[string]interface{}{
+				"p:foo_jim": "bean",
+				"p:fee":     "bar",
+			},
+			"p:",
+			UnderscoreStyle,
+		},
+		// Test case 5
+		{
+			"custom paren style",
+			map[string]interface{}{
+				"a(b)(c)(d)": "e",
+			},
+			"",
+			SeparatorStyle{Before: "(", After: ")"},
+		},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("test: %v (%s)", i, test.test), func(t *testing.T) {
+			nested, err := Unflatten(test.flat, test.prefix, test.style)
+			assert.NoError(t, err)
+
+			reflattened, err := Flatten(nested, test.prefix, test.style, -1)
+			assert.NoError(t, err)
+			deepEquals(t, i, reflattened, test.flat)
+		})
+	}
+}
+
+func TestUnflattenKeepsRootAMapEvenWithIndexedKeys(t *testing.T) {
+	flat := map[string]interface{}{
+		"0": "a",
+		"1": "b",
+	}
+
+	nested, err := Unflatten(flat, "", DotStyle)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"0": "a", "1": "b"}, nested)
+}
+
+func TestUnflattenRejectsNonCanonicalIndexKeys(t *testing.T) {
+	flat := map[string]interface{}{
+		"a.0":  "x",
+		"a.00": "y",
+		"a.1":  "z",
+	}
+
+	nested, err := Unflatten(flat, "", DotStyle)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{"0": "x", "00": "y", "1": "z"},
+	}, nested)
+}
+
+func TestUnflattenRebuildsArrays(t *testing.T) {
+	flat := map[string]interface{}{
+		"alist.0": "a",
+		"alist.1": "b",
+		"alist.2": "c",
+	}
+
+	nested, err := Unflatten(flat, "", DotStyle)
+	assert.NoError(t, err)
+
+	arr, ok := nested["alist"].([]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{"a", "b", "c"}, arr)
+}
+
+func TestUnflattenString(t *testing.T) {
+	got, err := UnflattenString(`{ "a.b.c.d": "e", "number": 1.4567, "bool": true }`, "", DotStyle)
+	assert.NoError(t, err)
+
+	var gotMap map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(got), &gotMap))
+
+	want := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{
+				"c": map[string]interface{}{
+					"d": "e",
+				},
+			},
+		},
+		"number": 1.4567,
+		"bool":   true,
+	}
+	assert.Equal(t, want, gotMap)
+}
+
+func TestUnflattenStringInvalidJSON(t *testing.T) {
+	_, err := UnflattenString(`[ "a": "b" ]`, "", DotStyle)
+	assert.ErrorIs(t, err, ErrNotValidJsonInput)
+}